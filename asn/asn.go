@@ -0,0 +1,184 @@
+// Package asn expands discovered IPs into the netblocks they belong to,
+// so reverse DNS can be swept across neighboring infrastructure owned by
+// the same network operator. ASN data comes from Team Cymru's public DNS
+// lookup service; reverse DNS comes from the system resolver, since it's
+// routine information rather than something a hijacked resolver would
+// have reason to lie about.
+package asn
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ASNInfo describes the autonomous system an IP was found to belong to.
+type ASNInfo struct {
+	ASN      string // e.g. "13335"
+	Netblock string // announced BGP prefix the IP falls within, e.g. "104.16.0.0/13"
+	Name     string // registered AS name, e.g. "CLOUDFLARENET"
+	Country  string
+}
+
+// LookupASN resolves ip's owning autonomous system via Team Cymru's DNS
+// origin query (origin.asn.cymru.com), then looks up the AS's registered
+// name via their companion asn.cymru.com query.
+func LookupASN(ip string) (ASNInfo, error) {
+	reversed, err := reverseIP(ip)
+	if err != nil {
+		return ASNInfo{}, err
+	}
+
+	records, err := net.LookupTXT(reversed + ".origin.asn.cymru.com")
+	if err != nil {
+		return ASNInfo{}, fmt.Errorf("asn: origin lookup for %s: %w", ip, err)
+	}
+	if len(records) == 0 {
+		return ASNInfo{}, fmt.Errorf("asn: no origin record for %s", ip)
+	}
+
+	// "ASN | BGP Prefix | CC | Registry | Allocated"
+	fields := splitFields(records[0])
+	if len(fields) < 3 {
+		return ASNInfo{}, fmt.Errorf("asn: malformed origin record %q", records[0])
+	}
+	info := ASNInfo{ASN: fields[0], Netblock: fields[1], Country: fields[2]}
+
+	if nameRecords, err := net.LookupTXT("AS" + info.ASN + ".asn.cymru.com"); err == nil && len(nameRecords) > 0 {
+		// "ASN | CC | Registry | Allocated | AS Name"
+		nameFields := splitFields(nameRecords[0])
+		if len(nameFields) >= 5 {
+			info.Name = nameFields[4]
+		}
+	}
+
+	return info, nil
+}
+
+// splitFields splits a Cymru "|"-delimited TXT record into trimmed fields.
+func splitFields(record string) []string {
+	parts := strings.Split(record, "|")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+// reverseIP builds the in-addr query label Cymru expects: the IPv4
+// octets reversed, e.g. "1.2.3.4" -> "4.3.2.1".
+func reverseIP(ip string) (string, error) {
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return "", fmt.Errorf("asn: %q is not an IPv4 address", ip)
+	}
+	return fmt.Sprintf("%d.%d.%d.%d", parsed[3], parsed[2], parsed[1], parsed[0]), nil
+}
+
+// NetblockPrefixLen returns the prefix length of a CIDR netblock, e.g. 24
+// for "192.0.2.0/24".
+func NetblockPrefixLen(cidr string) (int, error) {
+	idx := strings.LastIndex(cidr, "/")
+	if idx == -1 {
+		return 0, fmt.Errorf("asn: %q has no prefix length", cidr)
+	}
+	length, err := strconv.Atoi(cidr[idx+1:])
+	if err != nil {
+		return 0, fmt.Errorf("asn: %q has an invalid prefix length", cidr)
+	}
+	return length, nil
+}
+
+// SweepNetblock walks every host address in cidr, performs a reverse DNS
+// lookup, and returns the subset of hostnames found that belong to
+// filterDomain. Lookups run concurrently across a small worker pool since
+// even a capped /24 sweep is 254 addresses.
+func SweepNetblock(cidr, filterDomain string) []string {
+	ips, err := hostAddresses(cidr)
+	if err != nil {
+		return nil
+	}
+
+	const workers = 20
+	jobs := make(chan string)
+	results := make(chan string)
+	done := make(chan struct{})
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for ip := range jobs {
+				names, err := net.LookupAddr(ip)
+				if err != nil {
+					continue
+				}
+				for _, name := range names {
+					name = strings.ToLower(strings.TrimSuffix(name, "."))
+					if name == filterDomain || strings.HasSuffix(name, "."+filterDomain) {
+						results <- name
+					}
+				}
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	go func() {
+		for _, ip := range ips {
+			jobs <- ip
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		for i := 0; i < workers; i++ {
+			<-done
+		}
+		close(results)
+	}()
+
+	var found []string
+	for name := range results {
+		found = append(found, name)
+	}
+	return found
+}
+
+// hostAddresses enumerates every host address in cidr (excluding the
+// network and broadcast addresses of a IPv4 block).
+func hostAddresses(cidr string) ([]string, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	ip = ip.Mask(ipNet.Mask).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("asn: %q is not an IPv4 netblock", cidr)
+	}
+
+	var addrs []string
+	for current := cloneIP(ip); ipNet.Contains(current); incIP(current) {
+		addrs = append(addrs, current.String())
+	}
+
+	// Drop the network and broadcast addresses when the block is large
+	// enough to have them.
+	if len(addrs) > 2 {
+		addrs = addrs[1 : len(addrs)-1]
+	}
+	return addrs, nil
+}
+
+func cloneIP(ip net.IP) net.IP {
+	clone := make(net.IP, len(ip))
+	copy(clone, ip)
+	return clone
+}
+
+func incIP(ip net.IP) {
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			break
+		}
+	}
+}
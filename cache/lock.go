@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockTimeout bounds how long Save waits for another process to release
+// the cache file's lock before giving up.
+const lockTimeout = 5 * time.Second
+
+// Lock acquires an advisory, exclusive-create lock file alongside path,
+// retrying until it succeeds or lockTimeout elapses. It returns a
+// function that releases the lock. Exported so other state files under
+// ~/.xorn (such as the CT log cursor) can guard their own
+// load-merge-write critical sections the same way Store.Save does.
+func Lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("cache: timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
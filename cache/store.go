@@ -0,0 +1,205 @@
+// Package cache implements a persistent, on-disk cache of DNS
+// resolution results keyed by (subdomain, resolver), so repeated scans
+// of the same target don't redo every lookup. Entries are stored with a
+// TTL - honoring the authoritative DNS TTL when it's known, and a
+// shorter one for negative (NXDOMAIN) results - and the cache file is
+// safe to share across concurrent scans via an advisory lock.
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is used for positive entries when the DNS answer carried no
+// usable TTL.
+const DefaultTTL = 24 * time.Hour
+
+// NegativeTTL is used for NXDOMAIN entries, which are far more likely to
+// go stale (a name can be registered at any time) than a positive one.
+const NegativeTTL = 30 * time.Minute
+
+// Entry is a single cached resolution result.
+type Entry struct {
+	IPs       []string
+	CNAMEs    []string
+	Negative  bool
+	ExpiresAt time.Time
+}
+
+// expired reports whether the entry is past its TTL as of now.
+func (e Entry) expired(now time.Time) bool {
+	return now.After(e.ExpiresAt)
+}
+
+// Store is a persistent, file-backed cache of Entry values.
+type Store struct {
+	path        string
+	fallbackTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// SetFallbackTTL overrides the TTL used by SetResolved for answers that
+// carried no usable DNS TTL. It defaults to DefaultTTL.
+func (s *Store) SetFallbackTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallbackTTL = ttl
+}
+
+// Key builds the cache key for a subdomain as seen by a specific
+// resolver; the resolver address is part of the key because a hijacked
+// or misconfigured resolver could answer the same name differently.
+func Key(subdomain, resolverAddr string) string {
+	return subdomain + "|" + resolverAddr
+}
+
+// Dir returns xorn's per-user state directory (~/.xorn), creating it if
+// it doesn't already exist. It holds the resolution cache and anything
+// else xorn persists between runs, such as the CT log cursor.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".xorn")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// DefaultPath returns ~/.xorn/cache.db, creating the ~/.xorn directory
+// if it doesn't already exist.
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache.db"), nil
+}
+
+// Open loads the Store at path, starting from an empty cache if the
+// file doesn't exist yet.
+func Open(path string) (*Store, error) {
+	entries, err := loadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: path, fallbackTTL: DefaultTTL, entries: entries}, nil
+}
+
+// loadEntries reads and decodes the entries stored at path, returning an
+// empty map if the file doesn't exist yet or is empty.
+func loadEntries(path string) (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return entries, nil
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Get returns the cached entry for key, if any and not expired.
+func (s *Store) Get(key string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// SetResolved records a positive result, using ttl if positive or the
+// store's fallback TTL otherwise.
+func (s *Store) SetResolved(key string, ips, cnames []string, ttl time.Duration) {
+	if ttl <= 0 {
+		s.mu.Lock()
+		ttl = s.fallbackTTL
+		s.mu.Unlock()
+	}
+	s.set(key, Entry{IPs: ips, CNAMEs: cnames, ExpiresAt: time.Now().Add(ttl)})
+}
+
+// SetNegative records that key resolved to nothing (NXDOMAIN), using a
+// shorter TTL than a positive result would get.
+func (s *Store) SetNegative(key string) {
+	s.set(key, Entry{Negative: true, ExpiresAt: time.Now().Add(NegativeTTL)})
+}
+
+func (s *Store) set(key string, entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry
+}
+
+// Save merges the store's in-memory entries with whatever is currently
+// on disk and writes the result back, holding an advisory file lock
+// across the whole load-merge-write so that two concurrent scans don't
+// clobber each other's results - only blindly overwriting the file would
+// let the second Save erase entries the first one just wrote. Entries
+// already in memory win over their on-disk counterpart, whether this
+// process resolved them or loaded them when the store was opened. It writes to a temporary file and renames
+// it into place so a crash mid-write can't leave a truncated cache file.
+func (s *Store) Save() error {
+	unlock, err := Lock(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	onDisk, err := loadEntries(s.path)
+	if err != nil {
+		return err
+	}
+	for key, entry := range onDisk {
+		if _, ok := s.entries[key]; !ok {
+			s.entries[key] = entry
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s.entries); err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Purge deletes the cache file at path.
+func Purge(path string) error {
+	err := os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	got := Key("api.example.com", "1.1.1.1:53")
+	want := "api.example.com|1.1.1.1:53"
+	if got != want {
+		t.Errorf("Key(...) = %q, want %q", got, want)
+	}
+}
+
+// newStore opens a Store backed by a fresh file under t.TempDir().
+func newStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return store
+}
+
+func TestOpenMissingFileStartsEmpty(t *testing.T) {
+	store := newStore(t)
+	if _, ok := store.Get("anything"); ok {
+		t.Errorf("Get on a freshly opened, empty store should miss")
+	}
+}
+
+func TestSetResolvedThenGet(t *testing.T) {
+	store := newStore(t)
+
+	store.SetResolved("api.example.com|1.1.1.1", []string{"10.0.0.1"}, []string{"cname.example.com"}, time.Hour)
+
+	entry, ok := store.Get("api.example.com|1.1.1.1")
+	if !ok {
+		t.Fatalf("Get after SetResolved should hit")
+	}
+	if entry.Negative {
+		t.Errorf("entry should not be marked negative")
+	}
+	if len(entry.IPs) != 1 || entry.IPs[0] != "10.0.0.1" {
+		t.Errorf("entry.IPs = %v, want [10.0.0.1]", entry.IPs)
+	}
+	if len(entry.CNAMEs) != 1 || entry.CNAMEs[0] != "cname.example.com" {
+		t.Errorf("entry.CNAMEs = %v, want [cname.example.com]", entry.CNAMEs)
+	}
+}
+
+func TestSetResolvedZeroTTLUsesFallback(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	store.SetFallbackTTL(time.Minute)
+
+	before := time.Now()
+	store.SetResolved("key", []string{"10.0.0.1"}, nil, 0)
+
+	entry, ok := store.Get("key")
+	if !ok {
+		t.Fatalf("Get after SetResolved should hit")
+	}
+	if entry.ExpiresAt.Before(before.Add(time.Minute)) {
+		t.Errorf("entry.ExpiresAt = %v, want at least %v", entry.ExpiresAt, before.Add(time.Minute))
+	}
+}
+
+func TestSetNegative(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	store.SetNegative("nxdomain.example.com|1.1.1.1")
+
+	entry, ok := store.Get("nxdomain.example.com|1.1.1.1")
+	if !ok {
+		t.Fatalf("Get after SetNegative should hit")
+	}
+	if !entry.Negative {
+		t.Errorf("entry.Negative = false, want true")
+	}
+	if entry.IPs != nil {
+		t.Errorf("entry.IPs = %v, want nil for a negative entry", entry.IPs)
+	}
+}
+
+func TestGetExpiredEntryMisses(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	store.SetResolved("key", []string{"10.0.0.1"}, nil, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if _, ok := store.Get("key"); ok {
+		t.Errorf("Get on an expired entry should miss")
+	}
+}
+
+func TestSaveAndReopenRoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	store.SetResolved("key", []string{"10.0.0.1"}, nil, time.Hour)
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open (reopen): %v", err)
+	}
+	entry, ok := reopened.Get("key")
+	if !ok {
+		t.Fatalf("Get after reopening a saved store should hit")
+	}
+	if len(entry.IPs) != 1 || entry.IPs[0] != "10.0.0.1" {
+		t.Errorf("entry.IPs = %v, want [10.0.0.1]", entry.IPs)
+	}
+}
+
+// TestSaveMergesConcurrentWrites guards against the cache losing entries
+// when two scans share a cache file: each opens the store, resolves a
+// different name, and saves. Without merging against what's currently on
+// disk, the second Save would overwrite the first scan's entry entirely.
+func TestSaveMergesConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	first, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open (first): %v", err)
+	}
+	first.SetResolved("first.example.com", []string{"10.0.0.1"}, nil, time.Hour)
+
+	second, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open (second): %v", err)
+	}
+	second.SetResolved("second.example.com", []string{"10.0.0.2"}, nil, time.Hour)
+
+	if err := first.Save(); err != nil {
+		t.Fatalf("Save (first): %v", err)
+	}
+	if err := second.Save(); err != nil {
+		t.Fatalf("Save (second): %v", err)
+	}
+
+	final, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open (final): %v", err)
+	}
+	if _, ok := final.Get("first.example.com"); !ok {
+		t.Errorf("first scan's entry was lost after second scan's Save")
+	}
+	if _, ok := final.Get("second.example.com"); !ok {
+		t.Errorf("second scan's entry was lost")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	store.SetResolved("key", []string{"10.0.0.1"}, nil, time.Hour)
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := Purge(path); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open after purge: %v", err)
+	}
+	if _, ok := reopened.Get("key"); ok {
+		t.Errorf("Get after Purge should miss")
+	}
+}
+
+func TestPurgeMissingFileIsNotAnError(t *testing.T) {
+	if err := Purge(filepath.Join(t.TempDir(), "does-not-exist.db")); err != nil {
+		t.Errorf("Purge(missing file) = %v, want nil", err)
+	}
+}
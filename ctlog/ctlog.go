@@ -0,0 +1,133 @@
+// Package ctlog discovers subdomains from Certificate Transparency logs.
+// It covers crt.sh's indexed search for full historical coverage and,
+// when a lookback window is configured, tails entries directly from
+// public CT log servers (Google Argon/Xenon, Cloudflare Nimbus) via the
+// RFC 6962 get-entries API, so certificates issued since the last scan
+// show up before crt.sh gets around to indexing them.
+package ctlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LogServer is a CT log's base API URL, as used by the RFC 6962
+// get-sth/get-entries endpoints.
+type LogServer struct {
+	Name string
+	URL  string
+}
+
+// DefaultLogs is tailed only as a last resort, if FetchActiveLogs fails
+// and the caller supplied no explicit override. CT log shards are
+// retired and replaced every six to twelve months, so this list goes
+// stale; prefer letting CTLog fetch the live list, or configure logs
+// explicitly via sources.yaml's ct_logs key.
+var DefaultLogs = []LogServer{
+	{Name: "argon2025h1", URL: "https://ct.googleapis.com/logs/us1/argon2025h1/"},
+	{Name: "xenon2025h1", URL: "https://ct.googleapis.com/logs/eu1/xenon2025h1/"},
+	{Name: "nimbus2025", URL: "https://ct.cloudflare.com/logs/nimbus2025/"},
+}
+
+// CTLog enumerates subdomains seen in certificate transparency logs. It
+// implements passive.Source.
+type CTLog struct {
+	client *http.Client
+	logs   []LogServer // explicit override; empty means fetch the live list
+	since  time.Duration
+	cursor *Cursor
+}
+
+// NewCTLog returns a Source that searches crt.sh for domain's full
+// certificate history and, if since is positive, additionally tails CT
+// logs for entries issued within the last since. With no explicit logs
+// given, it fetches the live, currently-usable log list on each call
+// rather than tailing a fixed, eventually-retired shard; logs lets a
+// caller (e.g. a sources.yaml ct_logs entry) pin a specific set instead.
+// cursor may be nil, in which case tailing always starts from the tip of
+// since rather than resuming from a prior scan.
+func NewCTLog(client *http.Client, since time.Duration, cursor *Cursor, logs []LogServer) *CTLog {
+	return &CTLog{client: client, logs: logs, since: since, cursor: cursor}
+}
+
+// Name implements passive.Source.
+func (c *CTLog) Name() string { return "ctlog" }
+
+// Enumerate implements passive.Source.
+func (c *CTLog) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+
+		for _, name := range c.crtsh(ctx, domain) {
+			select {
+			case out <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if c.since <= 0 {
+			return
+		}
+		for _, log := range c.activeLogs(ctx) {
+			for _, name := range c.tail(ctx, log, domain) {
+				select {
+				case out <- name:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// activeLogs returns the explicit log override if one was configured,
+// otherwise the live, currently-usable log list, falling back to
+// DefaultLogs only if that fetch fails.
+func (c *CTLog) activeLogs(ctx context.Context) []LogServer {
+	if len(c.logs) > 0 {
+		return c.logs
+	}
+	if logs, err := FetchActiveLogs(ctx, c.client); err == nil {
+		return logs
+	}
+	return DefaultLogs
+}
+
+type crtShEntry struct {
+	NameValue string `json:"name_value"`
+}
+
+// crtsh queries crt.sh's indexed search, the same full-history source
+// the "crtsh" passive source uses. Kept here too so -sources ctlog works
+// standalone without requiring "crtsh" alongside it.
+func (c *CTLog) crtsh(ctx context.Context, domain string) []string {
+	url := fmt.Sprintf("https://crt.sh/?q=%%.%s&output=json", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var entries []crtShEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.Split(entry.NameValue, "\n")...)
+	}
+	return names
+}
@@ -0,0 +1,120 @@
+package ctlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"xorn/cache"
+)
+
+// Cursor persists, per CT log, the tree index tailing has already
+// consumed up to, so a later scan with -ct-since resumes instead of
+// re-fetching the same recent window. Save merges against whatever is
+// currently on disk under an advisory lock, the same as cache.Store, so
+// two scans sharing a cursor file don't regress each other's progress.
+type Cursor struct {
+	path string
+
+	mu   sync.Mutex
+	next map[string]int64
+}
+
+// DefaultCursorPath returns ~/.xorn/ctlog-cursor.json, alongside the
+// resolution cache.
+func DefaultCursorPath() (string, error) {
+	dir, err := cache.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ctlog-cursor.json"), nil
+}
+
+// LoadCursor loads the Cursor at path, starting empty if the file
+// doesn't exist yet.
+func LoadCursor(path string) (*Cursor, error) {
+	next, err := loadNext(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor{path: path, next: next}, nil
+}
+
+// loadNext reads and decodes the per-log positions stored at path,
+// returning an empty map if the file doesn't exist yet or is empty.
+func loadNext(path string) (map[string]int64, error) {
+	next := make(map[string]int64)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return next, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return next, nil
+	}
+	if err := json.Unmarshal(data, &next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Get returns the tree index to resume log from, if this cursor has
+// seen it before.
+func (c *Cursor) Get(log string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	next, ok := c.next[log]
+	return next, ok
+}
+
+// Set records that log has been tailed up to (but not including) next.
+func (c *Cursor) Set(log string, next int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next[log] = next
+}
+
+// Save merges the cursor's in-memory positions with whatever is
+// currently on disk and writes the result back, holding an advisory
+// file lock across the whole load-merge-write so that two scans sharing
+// a cursor file don't clobber each other's progress - only blindly
+// overwriting the file would let the second Save regress a log the
+// first scan just tailed further than this process knows about. For a
+// log known both in memory and on disk, the further position wins. It
+// writes to a temp file and renames it into place so
+// a crash mid-write can't leave a truncated cursor file.
+func (c *Cursor) Save() error {
+	unlock, err := cache.Lock(c.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	onDisk, err := loadNext(c.path)
+	if err != nil {
+		return err
+	}
+	for log, next := range onDisk {
+		if current, ok := c.next[log]; !ok || next > current {
+			c.next[log] = next
+		}
+	}
+
+	data, err := json.Marshal(c.next)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
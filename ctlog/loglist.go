@@ -0,0 +1,61 @@
+package ctlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// logListURL is Google's combined, continuously-updated list of
+// qualified CT logs across every operator (Google, Cloudflare,
+// DigiCert, Sectigo, ...). Individual log shards are retired and
+// replaced every six to twelve months, so tailing a hardcoded shard
+// name eventually starts hitting a dead, frozen tree; fetching this
+// list lets tailing follow whichever shards are currently accepting
+// submissions without a code change.
+const logListURL = "https://www.gstatic.com/ct/log_list/v3/log_list.json"
+
+type logList struct {
+	Operators []struct {
+		Logs []struct {
+			URL   string              `json:"url"`
+			State map[string]struct{} `json:"state"`
+		} `json:"logs"`
+	} `json:"operators"`
+}
+
+// FetchActiveLogs downloads the current CT log list and returns every
+// log whose state is "usable" - i.e. currently accepting and merging
+// submissions, as opposed to pending, read-only, or retired.
+func FetchActiveLogs(ctx context.Context, client *http.Client) ([]LogServer, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logListURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list logList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	var logs []LogServer
+	for _, operator := range list.Operators {
+		for _, log := range operator.Logs {
+			if _, usable := log.State["usable"]; !usable {
+				continue
+			}
+			logs = append(logs, LogServer{Name: log.URL, URL: log.URL})
+		}
+	}
+	if len(logs) == 0 {
+		return nil, fmt.Errorf("ctlog: log list had no usable logs")
+	}
+	return logs, nil
+}
@@ -0,0 +1,91 @@
+package ctlog
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+)
+
+// oidSubjectAltName is the X.509 extension OID for subjectAltName
+// (id-ce-subjectAltName, 2.5.29.17).
+var oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+// dnsNameTag is the GeneralName CHOICE tag for dNSName (implicit
+// [2] IA5String), RFC 5280 section 4.2.1.6.
+const dnsNameTag = 2
+
+// sanNamesFromCert extracts the DNS SANs of a full, DER-encoded X.509
+// certificate leaf.
+func sanNamesFromCert(der []byte) []string {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil
+	}
+	return cert.DNSNames
+}
+
+// tbsCertificate mirrors the ASN.1 structure of RFC 5280's
+// TBSCertificate, just enough to reach the extensions list. A precert's
+// leaf_input carries this (minus the outer Certificate wrapper and
+// signature, and with the CT poison extension still present) rather
+// than a parseable x509.Certificate, so we walk it by hand instead of
+// calling x509.ParseCertificate.
+type tbsCertificate struct {
+	Raw           asn1.RawContent
+	Version       int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber  asn1.RawValue
+	Signature     asn1.RawValue
+	Issuer        asn1.RawValue
+	Validity      asn1.RawValue
+	Subject       asn1.RawValue
+	PublicKeyInfo asn1.RawValue
+	UniqueID1     asn1.RawValue   `asn1:"optional,tag:1"`
+	UniqueID2     asn1.RawValue   `asn1:"optional,tag:2"`
+	Extensions    []pkixExtension `asn1:"optional,explicit,tag:3"`
+}
+
+type pkixExtension struct {
+	Id       asn1.ObjectIdentifier
+	Critical bool `asn1:"optional"`
+	Value    []byte
+}
+
+// sanNamesFromTBS extracts the DNS SANs of a raw TBSCertificate, as seen
+// in a CT precertificate leaf entry.
+func sanNamesFromTBS(der []byte) []string {
+	var tbs tbsCertificate
+	if _, err := asn1.Unmarshal(der, &tbs); err != nil {
+		return nil
+	}
+
+	for _, ext := range tbs.Extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+		return dnsNamesFromGeneralNames(ext.Value)
+	}
+	return nil
+}
+
+// dnsNamesFromGeneralNames parses a SubjectAltName extension's DER value
+// (a SEQUENCE OF GeneralName) and returns the dNSName entries.
+func dnsNamesFromGeneralNames(value []byte) []string {
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(value, &raw); err != nil {
+		return nil
+	}
+
+	rest := raw.Bytes
+	var names []string
+	for len(rest) > 0 {
+		var name asn1.RawValue
+		remaining, err := asn1.Unmarshal(rest, &name)
+		if err != nil {
+			break
+		}
+		rest = remaining
+		if name.Class == asn1.ClassContextSpecific && name.Tag == dnsNameTag {
+			names = append(names, string(name.Bytes))
+		}
+	}
+	return names
+}
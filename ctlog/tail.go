@@ -0,0 +1,216 @@
+package ctlog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// entriesPerFetch bounds how many entries get-entries is asked for at
+// once; CT logs cap this server-side anyway (typically 1024), but asking
+// for less keeps a single failed fetch cheap to retry.
+const entriesPerFetch = 256
+
+type sth struct {
+	TreeSize int64 `json:"tree_size"`
+}
+
+type getEntriesResponse struct {
+	Entries []struct {
+		LeafInput string `json:"leaf_input"`
+	} `json:"entries"`
+}
+
+// tail fetches entries from log that were appended since the cursor's
+// last recorded position for it (or, with no cursor history, a window
+// sized by c.since), extracting any name the precert or leaf certificate
+// was issued for that belongs to domain.
+func (c *CTLog) tail(ctx context.Context, log LogServer, domain string) []string {
+	treeSize, err := c.getSTH(ctx, log)
+	if err != nil {
+		return nil
+	}
+
+	start := c.resumeFrom(log, treeSize)
+	if start >= treeSize {
+		return nil
+	}
+
+	var names []string
+	for start < treeSize {
+		end := start + entriesPerFetch - 1
+		if end >= treeSize {
+			end = treeSize - 1
+		}
+
+		leaves, err := c.getEntries(ctx, log, start, end)
+		if err != nil {
+			break
+		}
+		for _, leaf := range leaves {
+			for _, name := range namesForDomain(leaf, domain) {
+				names = append(names, name)
+			}
+		}
+
+		start = end + 1
+		if c.cursor != nil {
+			c.cursor.Set(log.Name, start)
+		}
+	}
+	return names
+}
+
+// resumeFrom returns the tree index to resume tailing log from: the
+// cursor's last position if we have one, otherwise a window of
+// recently-appended entries sized heuristically from c.since (CT logs
+// don't expose "entries since time T" directly, only by index range).
+func (c *CTLog) resumeFrom(log LogServer, treeSize int64) int64 {
+	if c.cursor != nil {
+		if next, ok := c.cursor.Get(log.Name); ok {
+			if next < 0 {
+				return 0
+			}
+			return next
+		}
+	}
+
+	// No prior cursor for this log: approximate "since" as a fraction of
+	// the tree rather than fetching its entire history on first use.
+	const assumedEntriesPerDay = 2_000_000
+	lookbackEntries := int64(c.since.Hours()/24*assumedEntriesPerDay) + 1
+	start := treeSize - lookbackEntries
+	if start < 0 {
+		start = 0
+	}
+	return start
+}
+
+func (c *CTLog) getSTH(ctx context.Context, log LogServer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, log.URL+"ct/v1/get-sth", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var parsed sth
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return 0, err
+	}
+	return parsed.TreeSize, nil
+}
+
+func (c *CTLog) getEntries(ctx context.Context, log LogServer, start, end int64) ([][]byte, error) {
+	url := fmt.Sprintf("%sct/v1/get-entries?start=%d&end=%d", log.URL, start, end)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var parsed getEntriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	leaves := make([][]byte, 0, len(parsed.Entries))
+	for _, entry := range parsed.Entries {
+		leaf, err := base64.StdEncoding.DecodeString(entry.LeafInput)
+		if err != nil {
+			continue
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
+
+// CT log entry types, RFC 6962 section 3.1.
+const (
+	entryTypeX509    = 0
+	entryTypePrecert = 1
+)
+
+// namesForDomain parses an RFC 6962 MerkleTreeLeaf and returns whatever
+// DNS names its certificate (or precertificate) was issued for that
+// belong to domain.
+func namesForDomain(leaf []byte, domain string) []string {
+	cert, err := parseMerkleTreeLeaf(leaf)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, name := range cert.dnsNames {
+		if name == domain || (len(name) > len(domain) && name[len(name)-len(domain)-1] == '.' && name[len(name)-len(domain):] == domain) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type leafCert struct {
+	dnsNames []string
+}
+
+// parseMerkleTreeLeaf decodes the TimestampedEntry within a MerkleTreeLeaf
+// and extracts the SANs of its certificate or precertificate.
+//
+//	struct {
+//	    Version version;        // 1 byte
+//	    MerkleLeafType type;    // 1 byte
+//	    uint64 timestamp;       // 8 bytes
+//	    LogEntryType entryType; // 2 bytes
+//	    ...                     // entry-type-specific body
+//	} MerkleTreeLeaf;
+func parseMerkleTreeLeaf(leaf []byte) (leafCert, error) {
+	if len(leaf) < 12 {
+		return leafCert{}, fmt.Errorf("ctlog: leaf too short")
+	}
+	entryType := binary.BigEndian.Uint16(leaf[10:12])
+	body := leaf[12:]
+
+	switch entryType {
+	case entryTypeX509:
+		der, _, err := readUint24Prefixed(body)
+		if err != nil {
+			return leafCert{}, err
+		}
+		return leafCert{dnsNames: sanNamesFromCert(der)}, nil
+	case entryTypePrecert:
+		if len(body) < 32 {
+			return leafCert{}, fmt.Errorf("ctlog: precert entry too short")
+		}
+		tbs, _, err := readUint24Prefixed(body[32:])
+		if err != nil {
+			return leafCert{}, err
+		}
+		return leafCert{dnsNames: sanNamesFromTBS(tbs)}, nil
+	default:
+		return leafCert{}, fmt.Errorf("ctlog: unknown entry type %d", entryType)
+	}
+}
+
+// readUint24Prefixed reads a TLS-style <1..2^24-1> opaque vector: a
+// 3-byte big-endian length followed by that many bytes.
+func readUint24Prefixed(b []byte) (data, rest []byte, err error) {
+	if len(b) < 3 {
+		return nil, nil, fmt.Errorf("ctlog: truncated length prefix")
+	}
+	length := int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+	b = b[3:]
+	if len(b) < length {
+		return nil, nil, fmt.Errorf("ctlog: truncated vector")
+	}
+	return b[:length], b[length:], nil
+}
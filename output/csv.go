@@ -0,0 +1,67 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var csvHeader = []string{
+	"subdomain", "ips", "cnames", "status_code", "title", "server",
+	"content_length", "tls_cert_sans", "source", "discovered_at",
+}
+
+// CSVWriter renders Results as CSV rows, writing the header before the
+// first result.
+type CSVWriter struct {
+	mu          sync.Mutex
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter returns a Writer that emits CSV rows.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write implements Writer.
+func (c *CSVWriter) Write(r Result) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	row := []string{
+		r.Subdomain,
+		strings.Join(r.IPs, "|"),
+		strings.Join(r.CNAMEs, "|"),
+		strconv.Itoa(r.StatusCode),
+		r.Title,
+		r.Server,
+		strconv.FormatInt(r.ContentLength, 10),
+		strings.Join(r.TLSCertSANs, "|"),
+		r.Source,
+		r.DiscoveredAt.Format(time.RFC3339),
+	}
+	if err := c.w.Write(row); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Close implements Writer.
+func (c *CSVWriter) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONWriter buffers every Result in memory and renders them as a single
+// JSON array on Close, since a valid JSON array can't be streamed
+// incrementally the way JSONL can.
+type JSONWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	results []Result
+}
+
+// NewJSONWriter returns a Writer that emits a JSON array of all results
+// once Close is called.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w}
+}
+
+// Write implements Writer.
+func (j *JSONWriter) Write(r Result) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.results = append(j.results, r)
+	return nil
+}
+
+// Close implements Writer.
+func (j *JSONWriter) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	enc := json.NewEncoder(j.w)
+	enc.SetIndent("", "  ")
+	results := j.results
+	if results == nil {
+		results = []Result{}
+	}
+	return enc.Encode(results)
+}
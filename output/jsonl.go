@@ -0,0 +1,42 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONLWriter renders each Result as a single JSON object followed by a
+// newline, streaming results as they arrive rather than buffering the
+// whole scan.
+type JSONLWriter struct {
+	mu  sync.Mutex
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLWriter returns a Writer that streams newline-delimited JSON.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	buffered := bufio.NewWriter(w)
+	return &JSONLWriter{w: buffered, enc: json.NewEncoder(buffered)}
+}
+
+// Write implements Writer. It flushes after every result so a consumer
+// piping stdout into jq or tail -f sees it immediately, matching the
+// streaming behavior this type promises.
+func (j *JSONLWriter) Write(r Result) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.enc.Encode(r); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+// Close implements Writer.
+func (j *JSONLWriter) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.w.Flush()
+}
@@ -0,0 +1,22 @@
+// Package output defines the structured scan result record and the
+// pluggable writers that serialize it, so Xorn can be driven as a
+// library and piped into tools like httpx, nuclei, or jq instead of
+// only printing plain lines to a terminal.
+package output
+
+import "time"
+
+// Result is a single discovered subdomain and everything learned about
+// it during the scan.
+type Result struct {
+	Subdomain     string    `json:"subdomain"`
+	IPs           []string  `json:"ips,omitempty"`
+	CNAMEs        []string  `json:"cnames,omitempty"`
+	StatusCode    int       `json:"status_code,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	Server        string    `json:"server,omitempty"`
+	ContentLength int64     `json:"content_length,omitempty"`
+	TLSCertSANs   []string  `json:"tls_cert_sans,omitempty"`
+	Source        string    `json:"source,omitempty"`
+	DiscoveredAt  time.Time `json:"discovered_at"`
+}
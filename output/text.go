@@ -0,0 +1,51 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// TextWriter renders each Result as the single human-readable line Xorn
+// has always printed, e.g.:
+//
+//	Subdomain found: api.example.com (IPs: 1.2.3.4) | Status Code: 200 | Title: API
+type TextWriter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// NewTextWriter returns a Writer that renders plain, human-readable lines.
+func NewTextWriter(w io.Writer) *TextWriter {
+	return &TextWriter{w: bufio.NewWriter(w)}
+}
+
+// Write implements Writer. It flushes after every result so output
+// appears during a run instead of only once the scan finishes.
+func (t *TextWriter) Write(r Result) error {
+	var line strings.Builder
+	fmt.Fprintf(&line, "Subdomain found: %s (IPs: %s)", r.Subdomain, strings.Join(r.IPs, ", "))
+	if r.StatusCode != 0 {
+		fmt.Fprintf(&line, " | Status Code: %d", r.StatusCode)
+	}
+	if r.Title != "" {
+		fmt.Fprintf(&line, " | Title: %s", r.Title)
+	}
+	line.WriteByte('\n')
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.w.WriteString(line.String()); err != nil {
+		return err
+	}
+	return t.w.Flush()
+}
+
+// Close implements Writer.
+func (t *TextWriter) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.w.Flush()
+}
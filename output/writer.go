@@ -0,0 +1,44 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// Writer emits Results as they are discovered. Implementations must be
+// safe for concurrent use, since results arrive from multiple resolver
+// worker goroutines.
+type Writer interface {
+	// Write emits a single result.
+	Write(Result) error
+
+	// Close flushes any buffered output and releases resources. It does
+	// not close the underlying io.Writer.
+	Close() error
+}
+
+// Format identifies a Writer implementation.
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// New returns a Writer of the given format writing to w.
+func New(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case "", FormatText:
+		return NewTextWriter(w), nil
+	case FormatJSON:
+		return NewJSONWriter(w), nil
+	case FormatJSONL:
+		return NewJSONLWriter(w), nil
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	default:
+		return nil, fmt.Errorf("output: unknown format %q", format)
+	}
+}
@@ -0,0 +1,68 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AlienVaultOTX queries AlienVault's Open Threat Exchange passive DNS
+// API, which is free to use without a key but accepts one to raise rate
+// limits.
+type AlienVaultOTX struct {
+	client *http.Client
+	apiKey string
+}
+
+// NewAlienVaultOTX returns a Source backed by the OTX passive DNS API.
+// apiKey may be empty; OTX serves anonymous requests at a lower rate limit.
+func NewAlienVaultOTX(client *http.Client, apiKey string) *AlienVaultOTX {
+	return &AlienVaultOTX{client: client, apiKey: apiKey}
+}
+
+// Name implements Source.
+func (s *AlienVaultOTX) Name() string { return "otx" }
+
+type otxResponse struct {
+	PassiveDNS []struct {
+		Hostname string `json:"hostname"`
+	} `json:"passive_dns"`
+}
+
+// Enumerate implements Source.
+func (s *AlienVaultOTX) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.apiKey != "" {
+		req.Header.Set("X-OTX-API-KEY", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed otxResponse
+	err = json.NewDecoder(resp.Body).Decode(&parsed)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, record := range parsed.PassiveDNS {
+			select {
+			case out <- record.Hostname:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,75 @@
+package passive
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"xorn/cache"
+)
+
+// httpTimeout bounds every outgoing request made by a Source.
+const httpTimeout = 15 * time.Second
+
+// Config holds API keys for passive sources that require authentication.
+// It is loaded from a YAML file such as sources.yaml so that keys never
+// need to be hardcoded or passed on the command line:
+//
+//	sources:
+//	  securitytrails: YOUR_KEY
+//	  shodan: YOUR_KEY
+//	  otx: YOUR_KEY
+//	ct_logs:
+//	  - https://ct.example.com/logs/somelog2026h1/
+type Config struct {
+	Sources map[string]string `yaml:"sources"`
+
+	// CTLogs pins the "ctlog" source to a specific set of CT log base
+	// URLs instead of letting it fetch the live, currently-usable log
+	// list on every run.
+	CTLogs []string `yaml:"ct_logs"`
+}
+
+// DefaultConfigPath returns ~/.xorn/sources.yaml, alongside the
+// resolution cache. Keeping it under the per-user state directory
+// rather than the working directory means a filled-in API key doesn't
+// end up checked into whatever repo the user happens to be running
+// xorn from.
+func DefaultConfigPath() (string, error) {
+	dir, err := cache.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sources.yaml"), nil
+}
+
+// LoadConfig reads a Config from path. A missing file is not an error -
+// it simply yields a Config with no API keys, so paid sources stay
+// disabled until the user opts in.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// APIKey returns the configured key for the named source, or "" if none
+// was supplied. It is safe to call on a nil Config.
+func (c *Config) APIKey(name string) string {
+	if c == nil || c.Sources == nil {
+		return ""
+	}
+	return c.Sources[name]
+}
@@ -0,0 +1,63 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CrtSh queries crt.sh, a public search engine over certificate
+// transparency logs, for names seen in issued certificates.
+type CrtSh struct {
+	client *http.Client
+}
+
+// NewCrtSh returns a Source backed by crt.sh's JSON endpoint.
+func NewCrtSh(client *http.Client) *CrtSh {
+	return &CrtSh{client: client}
+}
+
+// Name implements Source.
+func (s *CrtSh) Name() string { return "crtsh" }
+
+type crtShEntry struct {
+	NameValue string `json:"name_value"`
+}
+
+// Enumerate implements Source.
+func (s *CrtSh) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://crt.sh/?q=%%.%s&output=json", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []crtShEntry
+	err = json.NewDecoder(resp.Body).Decode(&entries)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, entry := range entries {
+			for _, name := range strings.Split(entry.NameValue, "\n") {
+				select {
+				case out <- name:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
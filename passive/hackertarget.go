@@ -0,0 +1,61 @@
+package passive
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HackerTarget queries HackerTarget's free hostsearch API, which returns
+// a plain-text "subdomain,ip" list per line.
+type HackerTarget struct {
+	client *http.Client
+}
+
+// NewHackerTarget returns a Source backed by HackerTarget's hostsearch API.
+func NewHackerTarget(client *http.Client) *HackerTarget {
+	return &HackerTarget{client: client}
+}
+
+// Name implements Source.
+func (s *HackerTarget) Name() string { return "hackertarget" }
+
+// Enumerate implements Source.
+func (s *HackerTarget) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://api.hackertarget.com/hostsearch/?q=%s", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || strings.Contains(line, "API count exceeded") {
+				continue
+			}
+			name, _, found := strings.Cut(line, ",")
+			if !found {
+				continue
+			}
+			select {
+			case out <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package passive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RapidDNS scrapes rapiddns.io's subdomain search page, which has no
+// public API and returns results as an HTML table.
+type RapidDNS struct {
+	client *http.Client
+}
+
+// NewRapidDNS returns a Source backed by rapiddns.io.
+func NewRapidDNS(client *http.Client) *RapidDNS {
+	return &RapidDNS{client: client}
+}
+
+// Name implements Source.
+func (s *RapidDNS) Name() string { return "rapiddns" }
+
+// Enumerate implements Source.
+func (s *RapidDNS) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://rapiddns.io/subdomain/%s?full=1", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		doc.Find("table#table tbody tr td:first-child").Each(func(_ int, cell *goquery.Selection) {
+			name := strings.TrimSpace(cell.Text())
+			if name == "" {
+				return
+			}
+			select {
+			case out <- name:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package passive
+
+import (
+	"net/http"
+	"time"
+
+	"xorn/ctlog"
+)
+
+// All is the list of source names queried, in order, when no source
+// names are given (-sources left empty). The key-gated "securitytrails"
+// and "shodan" sources are not in it and run only when named. "ctlog" is
+// excluded too: without a lookback window it only duplicates "crtsh", so
+// it's opt-in via -sources or implied by a positive ctSince.
+var All = []string{"crtsh", "hackertarget", "otx", "wayback", "rapiddns"}
+
+// ByNames builds the Source implementations for the given source names,
+// silently skipping names that are not registered. An empty names slice
+// resolves to All. ctSince and cursor configure the
+// "ctlog" source's CT-log-tailing tier; cursor may be nil.
+func ByNames(names []string, cfg *Config, ctSince time.Duration, cursor *ctlog.Cursor) []Source {
+	if len(names) == 0 {
+		names = All
+	}
+
+	client := &http.Client{Timeout: httpTimeout}
+
+	var sources []Source
+	for _, name := range names {
+		switch name {
+		case "crtsh":
+			sources = append(sources, NewCrtSh(client))
+		case "hackertarget":
+			sources = append(sources, NewHackerTarget(client))
+		case "otx":
+			sources = append(sources, NewAlienVaultOTX(client, cfg.APIKey("otx")))
+		case "wayback":
+			sources = append(sources, NewWayback(client))
+		case "rapiddns":
+			sources = append(sources, NewRapidDNS(client))
+		case "securitytrails":
+			if key := cfg.APIKey("securitytrails"); key != "" {
+				sources = append(sources, NewSecurityTrails(client, key))
+			}
+		case "shodan":
+			if key := cfg.APIKey("shodan"); key != "" {
+				sources = append(sources, NewShodan(client, key))
+			}
+		case "ctlog":
+			var logs []ctlog.LogServer
+			for _, url := range cfg.CTLogs {
+				logs = append(logs, ctlog.LogServer{Name: url, URL: url})
+			}
+			sources = append(sources, ctlog.NewCTLog(client, ctSince, cursor, logs))
+		}
+	}
+	return sources
+}
@@ -0,0 +1,62 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SecurityTrails queries the SecurityTrails subdomains API, a paid
+// service that requires an API key configured in sources.yaml.
+type SecurityTrails struct {
+	client *http.Client
+	apiKey string
+}
+
+// NewSecurityTrails returns a Source backed by the SecurityTrails API.
+func NewSecurityTrails(client *http.Client, apiKey string) *SecurityTrails {
+	return &SecurityTrails{client: client, apiKey: apiKey}
+}
+
+// Name implements Source.
+func (s *SecurityTrails) Name() string { return "securitytrails" }
+
+type securityTrailsResponse struct {
+	Subdomains []string `json:"subdomains"`
+}
+
+// Enumerate implements Source.
+func (s *SecurityTrails) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://api.securitytrails.com/v1/domain/%s/subdomains", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("APIKEY", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed securityTrailsResponse
+	err = json.NewDecoder(resp.Body).Decode(&parsed)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, sub := range parsed.Subdomains {
+			select {
+			case out <- fmt.Sprintf("%s.%s", sub, domain):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,61 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Shodan queries Shodan's DNS domain API, a paid service that requires
+// an API key configured in sources.yaml.
+type Shodan struct {
+	client *http.Client
+	apiKey string
+}
+
+// NewShodan returns a Source backed by the Shodan DNS domain API.
+func NewShodan(client *http.Client, apiKey string) *Shodan {
+	return &Shodan{client: client, apiKey: apiKey}
+}
+
+// Name implements Source.
+func (s *Shodan) Name() string { return "shodan" }
+
+type shodanResponse struct {
+	Subdomains []string `json:"subdomains"`
+}
+
+// Enumerate implements Source.
+func (s *Shodan) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://api.shodan.io/dns/domain/%s?key=%s", domain, s.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed shodanResponse
+	err = json.NewDecoder(resp.Body).Decode(&parsed)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, sub := range parsed.Subdomains {
+			select {
+			case out <- fmt.Sprintf("%s.%s", sub, domain):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
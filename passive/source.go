@@ -0,0 +1,73 @@
+// Package passive implements passive subdomain discovery backed by public
+// OSINT data sources (certificate transparency logs, DNS aggregators, web
+// archives, ...). Results are names only; callers are expected to validate
+// them with active DNS resolution before trusting them.
+package passive
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Source is a single OSINT data source capable of enumerating subdomains
+// for a given domain. Implementations stream results on the returned
+// channel and close it once they are done (or the context is cancelled).
+type Source interface {
+	// Name is the short identifier used to select this source from the
+	// command line, e.g. "crtsh" or "wayback".
+	Name() string
+
+	// Enumerate queries the source for subdomains of domain. The returned
+	// channel is closed when the source has finished producing results.
+	Enumerate(ctx context.Context, domain string) (<-chan string, error)
+}
+
+// Aggregate runs every source concurrently against domain and returns the
+// deduplicated, sorted union of their results. A source that returns an
+// error is skipped; its failure does not affect the others.
+func Aggregate(ctx context.Context, domain string, sources []Source) []string {
+	var wg sync.WaitGroup
+	seen := make(map[string]struct{})
+	var mu sync.Mutex
+
+	for _, src := range sources {
+		ch, err := src.Enumerate(ctx, domain)
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(ch <-chan string) {
+			defer wg.Done()
+			for subdomain := range ch {
+				subdomain = normalize(subdomain)
+				if subdomain == "" {
+					continue
+				}
+				mu.Lock()
+				seen[subdomain] = struct{}{}
+				mu.Unlock()
+			}
+		}(ch)
+	}
+
+	wg.Wait()
+
+	results := make([]string, 0, len(seen))
+	for subdomain := range seen {
+		results = append(results, subdomain)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// normalize lowercases and trims a raw name returned by a source so that
+// results from different sources deduplicate against each other.
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimSuffix(name, ".")
+	name = strings.TrimPrefix(name, "*.")
+	return name
+}
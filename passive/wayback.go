@@ -0,0 +1,73 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Wayback mines the Internet Archive's CDX API for hostnames that were
+// ever crawled under domain, catching subdomains that have since been
+// decommissioned but left a trace in the archive.
+type Wayback struct {
+	client *http.Client
+}
+
+// NewWayback returns a Source backed by the Wayback Machine CDX API.
+func NewWayback(client *http.Client) *Wayback {
+	return &Wayback{client: client}
+}
+
+// Name implements Source.
+func (s *Wayback) Name() string { return "wayback" }
+
+// Enumerate implements Source.
+func (s *Wayback) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	cdxURL := fmt.Sprintf(
+		"https://web.archive.org/cdx/search/cdx?url=*.%s/*&output=json&fl=original&collapse=urlkey",
+		domain,
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cdxURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The CDX API returns a JSON array of rows, the first of which is a
+	// header rather than a result.
+	var rows [][]string
+	err = json.NewDecoder(resp.Body).Decode(&rows)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		rows = rows[1:]
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, row := range rows {
+			if len(row) == 0 {
+				continue
+			}
+			parsed, err := url.Parse(row[0])
+			if err != nil || parsed.Hostname() == "" {
+				continue
+			}
+			select {
+			case out <- parsed.Hostname():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,35 @@
+package permute
+
+import "sync"
+
+// Cache deduplicates permutation candidates across successive calls to
+// Generate, e.g. once per resolution iteration as newly discovered names
+// feed back into the permutation engine.
+type Cache struct {
+	mu      sync.Mutex
+	emitted map[string]struct{}
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{emitted: make(map[string]struct{})}
+}
+
+// Generate behaves like the package-level Generate, except candidates
+// already returned by a previous call on this Cache are filtered out.
+func (c *Cache) Generate(seen []string, config PermConfig) []string {
+	candidates := Generate(seen, config)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fresh := candidates[:0]
+	for _, candidate := range candidates {
+		if _, ok := c.emitted[candidate]; ok {
+			continue
+		}
+		c.emitted[candidate] = struct{}{}
+		fresh = append(fresh, candidate)
+	}
+	return fresh
+}
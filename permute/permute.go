@@ -0,0 +1,178 @@
+// Package permute generates subdomain name alterations from a set of
+// already-discovered names, modeled after Amass's "name alterations":
+// numeric suffixes, environment-token swaps, hyphen insertion/removal,
+// and concatenation of adjacent discovered labels. The generated
+// candidates are meant to be fed back through DNS resolution, not
+// trusted on their own.
+package permute
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PermConfig controls how permutations are generated.
+type PermConfig struct {
+	// EnvTokens are the environment tokens that get swapped for one
+	// another, e.g. {"dev", "stage", "prod", "qa"}. Defaults to
+	// {"dev", "stage", "staging", "prod", "qa"} when left empty.
+	EnvTokens []string
+
+	// MaxNumericSuffix is the highest numeric suffix appended to a label,
+	// e.g. 3 generates "api1", "api2", "api3". Defaults to 3.
+	MaxNumericSuffix int
+}
+
+var defaultEnvTokens = []string{"dev", "stage", "staging", "prod", "qa"}
+
+func (c PermConfig) withDefaults() PermConfig {
+	if len(c.EnvTokens) == 0 {
+		c.EnvTokens = defaultEnvTokens
+	}
+	if c.MaxNumericSuffix == 0 {
+		c.MaxNumericSuffix = 3
+	}
+	return c
+}
+
+var alphaNumBoundary = regexp.MustCompile(`([a-zA-Z])(\d)|(\d)([a-zA-Z])`)
+
+// Generate produces permutation candidates for the given already-discovered
+// subdomains. Candidates that are themselves already present in seen are
+// dropped, but no cross-call deduplication is performed here - use a Cache
+// for that.
+func Generate(seen []string, config PermConfig) []string {
+	config = config.withDefaults()
+
+	already := make(map[string]struct{}, len(seen))
+	for _, name := range seen {
+		already[name] = struct{}{}
+	}
+
+	candidates := make(map[string]struct{})
+	var firstLabels []string
+
+	for _, name := range seen {
+		label, rest, ok := splitLabel(name)
+		if !ok {
+			continue
+		}
+		firstLabels = append(firstLabels, label)
+
+		for _, alt := range numericSuffixes(label, config.MaxNumericSuffix) {
+			candidates[alt+"."+rest] = struct{}{}
+		}
+		for _, alt := range envSwaps(label, config.EnvTokens) {
+			candidates[alt+"."+rest] = struct{}{}
+		}
+		for _, alt := range hyphenVariants(label) {
+			candidates[alt+"."+rest] = struct{}{}
+		}
+	}
+
+	for _, name := range concatenateAdjacent(seen) {
+		candidates[name] = struct{}{}
+	}
+
+	results := make([]string, 0, len(candidates))
+	for candidate := range candidates {
+		if _, ok := already[candidate]; ok {
+			continue
+		}
+		results = append(results, candidate)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// splitLabel splits name into its leftmost label and the remaining parent
+// domain, e.g. "api.example.com" -> ("api", "example.com", true).
+func splitLabel(name string) (label, rest string, ok bool) {
+	label, rest, found := strings.Cut(name, ".")
+	if !found || label == "" || rest == "" {
+		return "", "", false
+	}
+	return label, rest, true
+}
+
+// numericSuffixes appends and hyphen-separates numeric suffixes up to max,
+// e.g. "api" -> "api1", "api-1", "api2", "api-2", ...
+func numericSuffixes(label string, max int) []string {
+	var out []string
+	for i := 1; i <= max; i++ {
+		n := strconv.Itoa(i)
+		out = append(out, label+n, label+"-"+n)
+	}
+	return out
+}
+
+// envSwaps replaces any environment token found in label (as a hyphenated
+// segment) with every other configured token.
+func envSwaps(label string, envTokens []string) []string {
+	segments := strings.Split(label, "-")
+
+	var out []string
+	for i, segment := range segments {
+		if !containsToken(envTokens, segment) {
+			continue
+		}
+		for _, token := range envTokens {
+			if token == segment {
+				continue
+			}
+			swapped := make([]string, len(segments))
+			copy(swapped, segments)
+			swapped[i] = token
+			out = append(out, strings.Join(swapped, "-"))
+		}
+	}
+	return out
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// hyphenVariants removes existing hyphens and inserts hyphens at
+// letter/digit boundaries, e.g. "api-dev" -> "apidev", "api2" -> "api-2".
+func hyphenVariants(label string) []string {
+	var out []string
+	if strings.Contains(label, "-") {
+		out = append(out, strings.ReplaceAll(label, "-", ""))
+	}
+	if hyphenated := alphaNumBoundary.ReplaceAllString(label, "$1$3-$2$4"); hyphenated != label {
+		out = append(out, hyphenated)
+	}
+	return out
+}
+
+// concatenateAdjacent concatenates adjacent discovered labels that share a
+// parent domain, e.g. ["api.example.com", "internal.example.com"] ->
+// "apiinternal.example.com".
+func concatenateAdjacent(seen []string) []string {
+	byParent := make(map[string][]string)
+	for _, name := range seen {
+		label, rest, ok := splitLabel(name)
+		if !ok {
+			continue
+		}
+		byParent[rest] = append(byParent[rest], label)
+	}
+
+	var out []string
+	for rest, labels := range byParent {
+		sort.Strings(labels)
+		for i := 0; i+1 < len(labels); i++ {
+			out = append(out, labels[i]+labels[i+1]+"."+rest)
+			out = append(out, labels[i]+"-"+labels[i+1]+"."+rest)
+		}
+	}
+	return out
+}
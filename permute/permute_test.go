@@ -0,0 +1,146 @@
+package permute
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		wantLabel string
+		wantRest  string
+		wantOK    bool
+	}{
+		{"normal name", "api.example.com", "api", "example.com", true},
+		{"bare label, no parent", "localhost", "", "", false},
+		{"trailing dot leaves an empty rest", "api.", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, rest, ok := splitLabel(tt.in)
+			if label != tt.wantLabel || rest != tt.wantRest || ok != tt.wantOK {
+				t.Errorf("splitLabel(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.in, label, rest, ok, tt.wantLabel, tt.wantRest, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNumericSuffixes(t *testing.T) {
+	got := numericSuffixes("api", 2)
+	want := []string{"api1", "api-1", "api2", "api-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("numericSuffixes(\"api\", 2) = %v, want %v", got, want)
+	}
+}
+
+func TestEnvSwaps(t *testing.T) {
+	tests := []struct {
+		name      string
+		label     string
+		envTokens []string
+		want      []string
+	}{
+		{
+			name:      "swaps a matching segment for every other token",
+			label:     "api-dev",
+			envTokens: []string{"dev", "stage", "prod"},
+			want:      []string{"api-stage", "api-prod"},
+		},
+		{
+			name:      "no match yields nothing",
+			label:     "api",
+			envTokens: []string{"dev", "prod"},
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := envSwaps(tt.label, tt.envTokens)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("envSwaps(%q, %v) = %v, want %v", tt.label, tt.envTokens, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHyphenVariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		want  []string
+	}{
+		{"removes an existing hyphen", "api-dev", []string{"apidev"}},
+		{"inserts a hyphen at a letter/digit boundary", "api2", []string{"api-2"}},
+		{"no boundary and no hyphen yields nothing", "api", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hyphenVariants(tt.label)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hyphenVariants(%q) = %v, want %v", tt.label, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcatenateAdjacent(t *testing.T) {
+	got := concatenateAdjacent([]string{"internal.example.com", "api.example.com", "other.example.net"})
+	want := []string{"apiinternal.example.com", "api-internal.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("concatenateAdjacent(...) = %v, want %v", got, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	config := PermConfig{EnvTokens: []string{"dev", "prod"}, MaxNumericSuffix: 1}
+
+	got := Generate([]string{"api-dev.example.com", "api1.example.com"}, config)
+
+	mustContain := []string{"api-prod.example.com", "api1-1.example.com"}
+	for _, want := range mustContain {
+		found := false
+		for _, candidate := range got {
+			if candidate == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Generate(...) = %v, want it to contain %q", got, want)
+		}
+	}
+
+	// Neither name passed in as seen should come back as a candidate -
+	// Generate should not resurface names already discovered.
+	for _, candidate := range got {
+		if candidate == "api1.example.com" || candidate == "api-dev.example.com" {
+			t.Errorf("Generate(...) = %v, should not include names already in seen", got)
+		}
+	}
+}
+
+func TestGenerateIsSorted(t *testing.T) {
+	got := Generate([]string{"zeta.example.com", "alpha.example.com"}, PermConfig{MaxNumericSuffix: 1})
+	for i := 1; i < len(got); i++ {
+		if got[i-1] > got[i] {
+			t.Errorf("Generate(...) = %v is not sorted", got)
+			break
+		}
+	}
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	got := Generate([]string{"api-dev.example.com"}, PermConfig{})
+	found := false
+	for _, candidate := range got {
+		if candidate == "api-prod.example.com" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Generate with a zero-value PermConfig should fall back to defaultEnvTokens, got %v", got)
+	}
+}
@@ -0,0 +1,196 @@
+// Package resolver implements DNS resolution against a pool of trusted
+// upstream resolvers, bypassing the system resolver (and whatever local
+// caching or hijacking it may be subject to). It is built on
+// github.com/miekg/dns so that per-query timeouts, NXDOMAIN/SERVFAIL
+// distinction, and wildcard detection are all under our control.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ErrNXDOMAIN indicates an authoritative negative answer: the name does
+// not exist. Callers should treat this as final and not retry.
+var ErrNXDOMAIN = errors.New("resolver: NXDOMAIN")
+
+// ErrServfail indicates the upstream resolver failed to produce an
+// answer. Unlike ErrNXDOMAIN this is transient and worth retrying,
+// ideally against a different upstream.
+var ErrServfail = errors.New("resolver: SERVFAIL")
+
+// DefaultUpstreams is used when no resolver pool is configured.
+var DefaultUpstreams = []string{"1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53"}
+
+// Pool round-robins DNS queries across a set of upstream resolvers.
+type Pool struct {
+	client *dns.Client
+
+	mu        sync.Mutex
+	upstreams []string
+	next      int
+}
+
+// NewPool returns a Pool that queries upstreams in round-robin order,
+// honoring timeout per query. Each entry in upstreams must be a
+// "host:port" address; NormalizeUpstream can prepare plain host/IP
+// input for this.
+func NewPool(upstreams []string, timeout time.Duration) *Pool {
+	if len(upstreams) == 0 {
+		upstreams = DefaultUpstreams
+	}
+	return &Pool{
+		client:    &dns.Client{Timeout: timeout},
+		upstreams: upstreams,
+	}
+}
+
+// Upstreams returns the pool's configured upstream addresses.
+func (p *Pool) Upstreams() []string {
+	upstreams := make([]string, len(p.upstreams))
+	copy(upstreams, p.upstreams)
+	return upstreams
+}
+
+// nextUpstream returns the next upstream address in round-robin order.
+func (p *Pool) nextUpstream() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	upstream := p.upstreams[p.next%len(p.upstreams)]
+	p.next++
+	return upstream
+}
+
+// IsTransient reports whether err is worth retrying. NXDOMAIN is an
+// authoritative negative and is never transient; everything else
+// (SERVFAIL, timeouts, network errors) is.
+func IsTransient(err error) bool {
+	return err != nil && !errors.Is(err, ErrNXDOMAIN)
+}
+
+// Lookup resolves name to its A and AAAA records in parallel, merging
+// the results. If both queries return NXDOMAIN, Lookup returns
+// ErrNXDOMAIN. If either query succeeds with at least one record, that
+// partial result is returned without error.
+func (p *Pool) Lookup(ctx context.Context, name string) ([]string, error) {
+	ips, _, err := p.LookupTTL(ctx, name)
+	return ips, err
+}
+
+// LookupTTL behaves like Lookup, additionally returning the lowest TTL
+// seen across the answer records, for callers (such as a result cache)
+// that want to honor how long the authoritative server says the answer
+// is valid for.
+func (p *Pool) LookupTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	type queryResult struct {
+		ips []string
+		ttl uint32
+		err error
+	}
+
+	results := make(chan queryResult, 2)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		go func(qtype uint16) {
+			ips, ttl, err := p.query(ctx, name, qtype)
+			results <- queryResult{ips: ips, ttl: ttl, err: err}
+		}(qtype)
+	}
+
+	var ips []string
+	var lastErr error
+	var minTTL uint32
+	nxdomainCount := 0
+	for i := 0; i < 2; i++ {
+		r := <-results
+		ips = append(ips, r.ips...)
+		if r.err != nil {
+			lastErr = r.err
+			if errors.Is(r.err, ErrNXDOMAIN) {
+				nxdomainCount++
+			}
+			continue
+		}
+		if minTTL == 0 || (r.ttl > 0 && r.ttl < minTTL) {
+			minTTL = r.ttl
+		}
+	}
+
+	if len(ips) > 0 {
+		return ips, time.Duration(minTTL) * time.Second, nil
+	}
+	if nxdomainCount == 2 {
+		return nil, 0, ErrNXDOMAIN
+	}
+	return nil, 0, lastErr
+}
+
+// LookupCNAME returns the single CNAME record name points at, or "" if
+// it has none. It does not follow the chain beyond that one hop.
+func (p *Pool) LookupCNAME(ctx context.Context, name string) (string, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), dns.TypeCNAME)
+	msg.RecursionDesired = true
+
+	upstream := p.nextUpstream()
+	resp, _, err := p.client.ExchangeContext(ctx, msg, upstream)
+	if err != nil {
+		return "", fmt.Errorf("resolver: query %s upstream %s: %w", name, upstream, err)
+	}
+
+	for _, answer := range resp.Answer {
+		if cname, ok := answer.(*dns.CNAME); ok {
+			return cname.Target, nil
+		}
+	}
+	return "", nil
+}
+
+// query performs a single query of the given type against the next
+// upstream in the pool, returning the lowest TTL among the answers.
+func (p *Pool) query(ctx context.Context, name string, qtype uint16) ([]string, uint32, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+
+	upstream := p.nextUpstream()
+	resp, _, err := p.client.ExchangeContext(ctx, msg, upstream)
+	if err != nil {
+		return nil, 0, fmt.Errorf("resolver: query %s upstream %s: %w", name, upstream, err)
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeNameError:
+		return nil, 0, ErrNXDOMAIN
+	case dns.RcodeServerFailure:
+		return nil, 0, ErrServfail
+	case dns.RcodeSuccess:
+		// fall through
+	default:
+		return nil, 0, fmt.Errorf("%w: rcode %s", ErrServfail, dns.RcodeToString[resp.Rcode])
+	}
+
+	var ips []string
+	var minTTL uint32
+	for _, answer := range resp.Answer {
+		var ttl uint32
+		switch record := answer.(type) {
+		case *dns.A:
+			ips = append(ips, record.A.String())
+			ttl = record.Hdr.Ttl
+		case *dns.AAAA:
+			ips = append(ips, record.AAAA.String())
+			ttl = record.Hdr.Ttl
+		default:
+			continue
+		}
+		if minTTL == 0 || ttl < minTTL {
+			minTTL = ttl
+		}
+	}
+	return ips, minTTL, nil
+}
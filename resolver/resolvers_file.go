@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+)
+
+// LoadResolversFile reads one resolver address per line (blank lines and
+// "#"-prefixed comments are ignored) and normalizes each to "host:port"
+// form, defaulting to port 53 when omitted.
+func LoadResolversFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var upstreams []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		upstreams = append(upstreams, NormalizeUpstream(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return upstreams, nil
+}
+
+// NormalizeUpstream appends the default DNS port to addr if it doesn't
+// already specify one.
+func NormalizeUpstream(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(addr, "53")
+}
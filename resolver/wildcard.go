@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// ProbeWildcard checks whether domain answers DNS queries for names that
+// were never registered (a wildcard record), which would otherwise make
+// every brute-forced candidate appear to resolve. It queries a random
+// label under domain and returns the IPs it answered with, or nil if the
+// probe came back NXDOMAIN (no wildcard).
+func (p *Pool) ProbeWildcard(ctx context.Context, domain string) ([]string, error) {
+	probe := fmt.Sprintf("%s.%s", randomLabel(), domain)
+
+	ips, err := p.Lookup(ctx, probe)
+	if err == ErrNXDOMAIN {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+// IsWildcardMatch reports whether ips is entirely contained in the
+// wildcard IP set discovered by ProbeWildcard, meaning the subdomain
+// that produced ips is indistinguishable from the wildcard catch-all
+// and should be discarded.
+func IsWildcardMatch(ips, wildcardIPs []string) bool {
+	if len(wildcardIPs) == 0 || len(ips) == 0 {
+		return false
+	}
+
+	wildcardSet := make(map[string]struct{}, len(wildcardIPs))
+	for _, ip := range wildcardIPs {
+		wildcardSet[ip] = struct{}{}
+	}
+	for _, ip := range ips {
+		if _, ok := wildcardSet[ip]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func randomLabel() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "xornwildcardprobe"
+	}
+	return hex.EncodeToString(buf)
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -12,41 +15,279 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"xorn/asn"
+	"xorn/cache"
+	"xorn/ctlog"
+	"xorn/output"
+	"xorn/passive"
+	"xorn/permute"
+	"xorn/resolver"
 )
 
 // Config represents the configuration for Xorn
 type Config struct {
-	Domain          string        // Domain to scan subdomains for
-	Threads         int           // Number of concurrent threads
-	Timeout         time.Duration // Timeout for DNS resolution
-	Retry           int           // Number of retry attempts for DNS resolution
-	RetryWait       time.Duration // Wait duration between retry attempts
-	OutputFile      string        // Output file to save results
-	OutputSeparator string        // Separator for output entries
-	WordlistFile    string        // Custom wordlist file for subdomain enumeration
-	RateLimit       int           // Rate limit for DNS queries (queries per second)
-	BatchSize       int           // Batch size for concurrent DNS resolutions
-	StatusCode      bool          // Check HTTP status code of subdomains
-	Title           bool          // Retrieve title of subdomains
+	Domain        string        // Domain to scan subdomains for
+	Threads       int           // Number of concurrent threads
+	Timeout       time.Duration // Timeout for DNS resolution
+	Retry         int           // Number of retry attempts for DNS resolution
+	RetryWait     time.Duration // Wait duration between retry attempts
+	OutputFile    string        // Output file to save results
+	OutputFormat  output.Format // Format results are written in: text, json, jsonl, or csv
+	WordlistFile  string        // Custom wordlist file for subdomain enumeration
+	RateLimit     int           // Rate limit for DNS queries (queries per second)
+	BatchSize     int           // Batch size for concurrent DNS resolutions
+	StatusCode    bool          // Check HTTP status code of subdomains
+	Title         bool          // Retrieve title of subdomains
+	Sources       []string      // Passive sources to query, e.g. []string{"crtsh", "wayback"}
+	SourcesConfig string        // Path to the YAML file holding API keys for passive sources (default: ~/.xorn/sources.yaml)
+	Passive       bool          // Run passive discovery only
+	Active        bool          // Run wordlist brute-force only
+	All           bool          // Run passive discovery and feed results into brute-force validation
+	Permute       bool          // Generate and resolve name alterations from discovered subdomains
+	PermEnvTokens []string      // Environment tokens swapped against each other during permutation
+	PermMaxSuffix int           // Highest numeric suffix generated during permutation
+	Resolvers     []string      // Trusted upstream DNS resolvers to query, e.g. []string{"1.1.1.1", "8.8.8.8"}
+	ResolversFile string        // Path to a file listing upstream DNS resolvers, one per line
+	NoCache       bool          // Disable the persistent on-disk resolution cache
+	CachePath     string        // Path to the persistent cache file (default: ~/.xorn/cache.db)
+	CacheTTL      time.Duration // TTL for cached positive results lacking a DNS-supplied TTL
+	AsnSweep      bool          // Expand discovered IPs into their announced netblocks and reverse-DNS sweep them
+	MaxNetblock   int           // Largest netblock (by prefix length, e.g. 24 for a /24) that AsnSweep will sweep
+	CTSince       time.Duration // If positive, tail CT logs for certificates issued within this window
 }
 
 // Scanner represents the Xorn subdomain scanner
 type Scanner struct {
-	config    Config
-	cache     map[string][]string // Cache for storing resolved subdomains and their IPs
-	cacheLock sync.Mutex          // Mutex for concurrent access to cache
+	config      Config
+	cacheStore  *cache.Store   // Persistent, on-disk resolution cache; nil when NoCache is set
+	resolverKey string         // Identifies this scanner's resolver pool for cache keys
+	permCache   *permute.Cache // Cache for deduplicating permutation candidates across passes
+	resolvers   *resolver.Pool // Pool of trusted upstream resolvers used in place of the system resolver
+	wildcardIPs []string       // IPs the target domain answers with for names that were never registered
+	writer      output.Writer  // Destination results are emitted to as they're discovered
+
+	ipMu sync.Mutex
+	ips  map[string]struct{} // IPs seen across every resolved subdomain this scan, for -asn-sweep
+
+	ctCursor *ctlog.Cursor // Persisted CT log tailing position, nil when CTSince is unset
 }
 
-// NewScanner creates a new instance of Scanner
+// NewScanner creates a new instance of Scanner. Results are written as
+// plain text to os.Stdout until SetWriter is called with something else.
 func NewScanner(config Config) *Scanner {
-	return &Scanner{
-		config: config,
-		cache:  make(map[string][]string),
+	upstreams := config.Resolvers
+	if config.ResolversFile != "" {
+		if fromFile, err := resolver.LoadResolversFile(config.ResolversFile); err == nil {
+			upstreams = append(upstreams, fromFile...)
+		}
+	}
+	for i, upstream := range upstreams {
+		upstreams[i] = resolver.NormalizeUpstream(upstream)
+	}
+
+	pool := resolver.NewPool(upstreams, config.Timeout)
+
+	scanner := &Scanner{
+		config:      config,
+		permCache:   permute.NewCache(),
+		resolvers:   pool,
+		resolverKey: strings.Join(pool.Upstreams(), ","),
+		writer:      output.NewTextWriter(os.Stdout),
+		ips:         make(map[string]struct{}),
+	}
+
+	if !config.NoCache {
+		path := config.CachePath
+		if path == "" {
+			if defaultPath, err := cache.DefaultPath(); err == nil {
+				path = defaultPath
+			}
+		}
+		if path != "" {
+			if store, err := cache.Open(path); err == nil {
+				store.SetFallbackTTL(config.CacheTTL)
+				scanner.cacheStore = store
+			} else {
+				fmt.Fprintln(os.Stderr, "Warning: could not open cache, continuing without it:", err)
+			}
+		}
+	}
+
+	if config.CTSince > 0 {
+		if path, err := ctlog.DefaultCursorPath(); err == nil {
+			if cursor, err := ctlog.LoadCursor(path); err == nil {
+				scanner.ctCursor = cursor
+			} else {
+				fmt.Fprintln(os.Stderr, "Warning: could not load CT log cursor, continuing without it:", err)
+			}
+		}
+	}
+
+	return scanner
+}
+
+// SaveCache persists the scanner's on-disk resolution cache, if caching
+// is enabled. Call it once scanning is done.
+func (s *Scanner) SaveCache() error {
+	if s.cacheStore == nil {
+		return nil
+	}
+	return s.cacheStore.Save()
+}
+
+// SaveCTCursor persists the scanner's CT log tailing position, if
+// -ct-since is in use. Call it once scanning is done.
+func (s *Scanner) SaveCTCursor() error {
+	if s.ctCursor == nil {
+		return nil
 	}
+	return s.ctCursor.Save()
+}
+
+// SetWriter replaces the destination results are emitted to. The caller
+// is responsible for calling Close on the writer once scanning is done.
+func (s *Scanner) SetWriter(w output.Writer) {
+	s.writer = w
+}
+
+// DetectWildcard probes the scanner's target domain for a wildcard DNS
+// record (a catch-all answer for names that were never registered) and
+// remembers the IPs it finds so resolveSubdomain can discard matches.
+// It is a no-op if the domain has no wildcard.
+func (s *Scanner) DetectWildcard(ctx context.Context) error {
+	ips, err := s.resolvers.ProbeWildcard(ctx, s.config.Domain)
+	if err != nil {
+		return err
+	}
+	s.wildcardIPs = ips
+	return nil
+}
+
+// DiscoverPassive queries every configured passive source for subdomains
+// of the scanner's target domain and returns the deduplicated union of
+// their results. It does not perform any DNS validation; callers should
+// feed the result through ScanPassiveSubdomains to confirm each name resolves.
+func (s *Scanner) DiscoverPassive(ctx context.Context) ([]string, error) {
+	sourcesConfig := s.config.SourcesConfig
+	if sourcesConfig == "" {
+		defaultPath, err := passive.DefaultConfigPath()
+		if err != nil {
+			return nil, err
+		}
+		sourcesConfig = defaultPath
+	}
+
+	cfg, err := passive.LoadConfig(sourcesConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	names := s.config.Sources
+	if s.config.CTSince > 0 && !contains(names, "ctlog") {
+		// Explicit sources (or the "all" default) may not include
+		// "ctlog"; a positive -ct-since means the user wants it tailed
+		// regardless.
+		if len(names) == 0 {
+			names = append(append([]string{}, passive.All...), "ctlog")
+		} else {
+			names = append(append([]string{}, names...), "ctlog")
+		}
+	}
+
+	sources := passive.ByNames(names, cfg, s.config.CTSince, s.ctCursor)
+	return passive.Aggregate(ctx, s.config.Domain, sources), nil
+}
+
+// contains reports whether names includes name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PermuteAndScan generates name alterations from discovered, feeds the
+// new (not yet seen this scan) candidates through the resolver, and
+// returns the subdomains that resolved. Call it repeatedly as discovered
+// grows to let alterations compound across passes; the scanner's
+// permutation cache prevents candidates from being regenerated and
+// re-resolved.
+func (s *Scanner) PermuteAndScan(discovered []string) []string {
+	config := permute.PermConfig{
+		EnvTokens:        s.config.PermEnvTokens,
+		MaxNumericSuffix: s.config.PermMaxSuffix,
+	}
+	candidates := s.permCache.Generate(discovered, config)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return s.scanSubdomains(candidates, "permute")
+}
+
+// ASNSweepAndScan looks up the owning ASN of every IP seen so far, sweeps
+// the announced netblocks small enough to stay within MaxNetblock via
+// reverse DNS, and resolves whatever names come back that belong to the
+// scanner's target domain. Call it after ScanSubdomains (and, if used,
+// PermuteAndScan) have populated the scanner's IP set.
+func (s *Scanner) ASNSweepAndScan(ctx context.Context) []string {
+	s.ipMu.Lock()
+	ips := make([]string, 0, len(s.ips))
+	for ip := range s.ips {
+		ips = append(ips, ip)
+	}
+	s.ipMu.Unlock()
+
+	seenNetblocks := make(map[string]struct{})
+	candidateSet := make(map[string]struct{})
+	for _, ip := range ips {
+		info, err := asn.LookupASN(ip)
+		if err != nil || info.Netblock == "" {
+			continue
+		}
+		if _, ok := seenNetblocks[info.Netblock]; ok {
+			continue
+		}
+		seenNetblocks[info.Netblock] = struct{}{}
+
+		prefixLen, err := asn.NetblockPrefixLen(info.Netblock)
+		if err != nil || prefixLen < s.config.MaxNetblock {
+			continue
+		}
+
+		for _, name := range asn.SweepNetblock(info.Netblock, s.config.Domain) {
+			candidateSet[name] = struct{}{}
+		}
+	}
+
+	if len(candidateSet) == 0 {
+		return nil
+	}
+	candidates := make([]string, 0, len(candidateSet))
+	for name := range candidateSet {
+		candidates = append(candidates, name)
+	}
+	return s.scanSubdomains(candidates, "asn-sweep")
 }
 
 // ScanSubdomains scans subdomains concurrently
 func (s *Scanner) ScanSubdomains(subdomains []string) []string {
+	return s.scanSubdomains(subdomains, "active")
+}
+
+// ScanPassiveSubdomains resolves subdomains discovered via passive
+// sources, tagging each Result's source as "passive" instead of
+// ScanSubdomains' "active" so structured output can tell them apart.
+func (s *Scanner) ScanPassiveSubdomains(subdomains []string) []string {
+	return s.scanSubdomains(subdomains, "passive")
+}
+
+// scanSubdomains is the shared worker pool behind ScanSubdomains and
+// PermuteAndScan; source is recorded on every Result so a downstream
+// consumer of structured output can tell where a name came from.
+func (s *Scanner) scanSubdomains(subdomains []string, source string) []string {
 	var wg sync.WaitGroup
 	resultCh := make(chan string)
 	batches := chunkSubdomains(subdomains, s.config.BatchSize)
@@ -65,7 +306,7 @@ func (s *Scanner) ScanSubdomains(subdomains []string) []string {
 			defer wg.Done()
 			for _, subdomain := range batch {
 				<-rateLimiter
-				if s.resolveSubdomain(subdomain) {
+				if s.resolveSubdomain(subdomain, source) {
 					resultCh <- subdomain
 				}
 				rateLimiter <- time.Now()
@@ -88,113 +329,152 @@ func (s *Scanner) ScanSubdomains(subdomains []string) []string {
 }
 
 // ResolveSubdomain checks if a subdomain resolves to an IP
-func (s *Scanner) resolveSubdomain(subdomain string) bool {
-	// Check cache first
-	s.cacheLock.Lock()
-	if ips, ok := s.cache[subdomain]; ok {
-		s.cacheLock.Unlock()
-		if len(ips) > 0 {
-			output := fmt.Sprintf("Subdomain found: %s (IPs: %s)", subdomain, strings.Join(ips, ", "))
-			if s.config.StatusCode {
-				output += " | Status Code: 200"
-			}
-			if s.config.Title {
-				title := getPageTitle("http://" + subdomain)
-				if title != "" {
-					output += " | Title: " + title
-				}
+func (s *Scanner) resolveSubdomain(subdomain, source string) bool {
+	cacheKey := cache.Key(subdomain, s.resolverKey)
+
+	// Check the persistent cache first
+	if s.cacheStore != nil {
+		if entry, ok := s.cacheStore.Get(cacheKey); ok {
+			if entry.Negative {
+				return false
 			}
-			fmt.Println(output)
+			s.emit(subdomain, entry.IPs, entry.CNAMEs, source)
 			return true
 		}
-		// Subdomain was previously resolved but had no IPs
-		return false
 	}
-	s.cacheLock.Unlock()
 
-	// Subdomain not found in cache, perform DNS resolution
+	// Not cached, perform DNS resolution against the trusted resolver
+	// pool. NXDOMAIN is an authoritative negative, so only transient
+	// failures (SERVFAIL, timeouts, ...) are retried.
+	var lastErr error
 	for i := 0; i < s.config.Retry; i++ {
-		ips, err := net.LookupIP(subdomain)
+		ips, ttl, err := s.resolvers.LookupTTL(context.Background(), subdomain)
 		if err == nil && len(ips) > 0 {
-			// Cache resolved IPs
-			s.cacheLock.Lock()
-			s.cache[subdomain] = ipsToStringSlice(ips)
-			s.cacheLock.Unlock()
-
-			// Print found subdomain
-			output := fmt.Sprintf("Subdomain found: %s (IPs: %s)", subdomain, strings.Join(ipsToStringSlice(ips), ", "))
-			if s.config.StatusCode {
-				output += " | Status Code: 200"
+			if resolver.IsWildcardMatch(ips, s.wildcardIPs) {
+				break
 			}
-			if s.config.Title {
-				title := getPageTitle("http://" + subdomain)
-				if title != "" {
-					output += " | Title: " + title
-				}
+
+			var cnames []string
+			if cname, err := s.resolvers.LookupCNAME(context.Background(), subdomain); err == nil && cname != "" {
+				cnames = []string{cname}
+			}
+
+			if s.cacheStore != nil {
+				s.cacheStore.SetResolved(cacheKey, ips, cnames, ttl)
 			}
-			fmt.Println(output)
+
+			s.emit(subdomain, ips, cnames, source)
 			return true
 		}
+		lastErr = err
+		if !resolver.IsTransient(err) {
+			break
+		}
 		time.Sleep(s.config.RetryWait)
 	}
 
-	// Cache unresolved subdomain
-	s.cacheLock.Lock()
-	s.cache[subdomain] = nil
-	s.cacheLock.Unlock()
+	// A name that only ever failed transiently is not known to be
+	// missing, so it isn't cached as a negative.
+	if s.cacheStore != nil && !resolver.IsTransient(lastErr) {
+		s.cacheStore.SetNegative(cacheKey)
+	}
 
 	return false
 }
 
-// getPageTitle retrieves the title of a webpage
-func getPageTitle(url string) string {
-	response, err := http.Get(url)
-	if err != nil {
-		return ""
+// emit builds a structured Result for a resolved subdomain and hands it
+// to the scanner's writer. HTTP enrichment (status code, title, server,
+// content length) is only attempted when the caller asked for it, since
+// it costs a real request per subdomain.
+func (s *Scanner) emit(subdomain string, ips, cnames []string, source string) {
+	if s.config.AsnSweep {
+		s.ipMu.Lock()
+		for _, ip := range ips {
+			s.ips[ip] = struct{}{}
+		}
+		s.ipMu.Unlock()
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		return ""
+	result := output.Result{
+		Subdomain:    subdomain,
+		IPs:          ips,
+		CNAMEs:       cnames,
+		Source:       source,
+		DiscoveredAt: time.Now(),
 	}
 
-	doc, err := goquery.NewDocumentFromReader(response.Body)
-	if err != nil {
-		return ""
+	if s.config.StatusCode || s.config.Title {
+		probe, err := probeHTTP(subdomain)
+		if err == nil {
+			result.StatusCode = probe.statusCode
+			result.Title = probe.title
+			result.Server = probe.server
+			result.ContentLength = probe.contentLength
+			result.TLSCertSANs = probe.tlsCertSANs
+		}
 	}
 
-	title := doc.Find("title").Text()
-	return strings.TrimSpace(title)
+	if err := s.writer.Write(result); err != nil {
+		fmt.Fprintln(os.Stderr, "Error writing result:", err)
+	}
 }
 
-// ipsToStringSlice converts a slice of net.IP to a slice of string
-func ipsToStringSlice(ips []net.IP) []string {
-	ipStrings := make([]string, len(ips))
-	for i, ip := range ips {
-		ipStrings[i] = ip.String()
-	}
-	return ipStrings
+// httpProbeResult holds the HTTP-derived fields of a Result.
+type httpProbeResult struct {
+	statusCode    int
+	title         string
+	server        string
+	contentLength int64
+	tlsCertSANs   []string
 }
 
-// WriteToFile writes the output to a file
-func (s *Scanner) WriteToFile(subdomains []string) error {
-	file, err := os.Create(s.config.OutputFile)
+// tlsProbeTimeout bounds how long probeHTTP's TLS handshake waits for a
+// certificate before giving up.
+const tlsProbeTimeout = 5 * time.Second
+
+// probeHTTP fetches the subdomain's homepage over HTTP to learn its
+// status code, page title, Server header, and content length, and
+// separately collects the DNS SANs of whatever TLS certificate it
+// presents on 443, if any.
+func probeHTTP(subdomain string) (httpProbeResult, error) {
+	response, err := http.Get("http://" + subdomain)
 	if err != nil {
-		return err
+		return httpProbeResult{}, err
 	}
-	defer file.Close()
+	defer response.Body.Close()
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
+	probe := httpProbeResult{
+		statusCode:    response.StatusCode,
+		server:        response.Header.Get("Server"),
+		contentLength: response.ContentLength,
+		tlsCertSANs:   tlsCertSANs(subdomain),
+	}
 
-	for _, subdomain := range subdomains {
-		_, err := writer.WriteString(subdomain + s.config.OutputSeparator + "\n")
-		if err != nil {
-			return err
-		}
+	if doc, err := goquery.NewDocumentFromReader(response.Body); err == nil {
+		probe.title = strings.TrimSpace(doc.Find("title").Text())
 	}
 
-	return nil
+	return probe, nil
+}
+
+// tlsCertSANs connects to subdomain on port 443 and returns the DNS SANs
+// of its leaf TLS certificate, or nil if the handshake fails (the
+// target may not serve TLS at all). Verification is skipped since the
+// goal is reading whatever certificate is presented, not validating
+// trust.
+func tlsCertSANs(subdomain string) []string {
+	dialer := &net.Dialer{Timeout: tlsProbeTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", subdomain+":443", &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil
+	}
+	return certs[0].DNSNames
 }
 
 // LoadWordlist loads subdomains from a wordlist file
@@ -222,6 +502,37 @@ func LoadWordlist(wordlistFile string) ([]string, error) {
 	return subdomains, nil
 }
 
+// dedupSubdomains returns subdomains with case-insensitive duplicates
+// removed, keeping the first occurrence of each name. Used to merge
+// wordlist and passive-discovery candidates without resolving (and
+// emitting) the same name twice.
+func dedupSubdomains(subdomains []string) []string {
+	return dedupAgainst(subdomains, nil)
+}
+
+// dedupAgainst returns subdomains with case-insensitive duplicates
+// removed, keeping the first occurrence of each name, and also
+// excluding any name already present in existing. Used to scan
+// wordlist and passive-discovery candidates separately (so each
+// Result's source reflects where it actually came from) while still
+// resolving and emitting every name only once overall.
+func dedupAgainst(subdomains, existing []string) []string {
+	seen := make(map[string]struct{}, len(existing)+len(subdomains))
+	for _, subdomain := range existing {
+		seen[strings.ToLower(subdomain)] = struct{}{}
+	}
+	deduped := make([]string, 0, len(subdomains))
+	for _, subdomain := range subdomains {
+		key := strings.ToLower(subdomain)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, subdomain)
+	}
+	return deduped
+}
+
 // chunkSubdomains divides the subdomains into batches
 func chunkSubdomains(subdomains []string, batchSize int) [][]string {
 	var batches [][]string
@@ -236,45 +547,124 @@ func chunkSubdomains(subdomains []string, batchSize int) [][]string {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		runCacheCommand(os.Args[2:])
+		return
+	}
+
 	// Parse command line flags
 	domainPtr := flag.String("d", "", "Domain to scan subdomains for")
 	threadsPtr := flag.Int("t", 100, "Number of concurrent threads")
 	timeoutPtr := flag.Duration("timeout", 2*time.Second, "Timeout for DNS resolution")
 	retryPtr := flag.Int("retry", 2, "Number of retry attempts for DNS resolution")
 	retryWaitPtr := flag.Duration("retry-wait", 100*time.Millisecond, "Wait duration between retry attempts")
-	outputFilePtr := flag.String("o", "", "Output file to save results")
-	outputSeparatorPtr := flag.String("separator", ",", "Separator for output entries")
+	outputFilePtr := flag.String("o", "", "Output file to save results (default: stdout)")
+	outputFormatPtr := flag.String("output-format", "", "Result format: text, json, jsonl, or csv (default: text)")
+	outputJSONPtr := flag.Bool("oJ", false, "Shorthand for -output-format json")
+	outputJSONLPtr := flag.Bool("oL", false, "Shorthand for -output-format jsonl")
+	outputCSVPtr := flag.Bool("oC", false, "Shorthand for -output-format csv")
+	outputTextPtr := flag.Bool("oT", false, "Shorthand for -output-format text")
 	wordlistFilePtr := flag.String("w", "", "Custom wordlist file for subdomain enumeration")
 	rateLimitPtr := flag.Int("rate-limit", 200, "Rate limit for DNS queries (queries per second)")
 	batchSizePtr := flag.Int("batch-size", 50, "Batch size for concurrent DNS resolutions")
 	statusCodePtr := flag.Bool("status-code", false, "Check HTTP status code of subdomains")
 	titlePtr := flag.Bool("title", false, "Retrieve title of subdomains")
+	sourcesPtr := flag.String("sources", "", "Comma-separated passive sources to query (default: crtsh,hackertarget,otx,wayback,rapiddns)")
+	sourcesConfigPtr := flag.String("sources-config", "", "Path to the YAML file holding API keys for passive sources (default: ~/.xorn/sources.yaml)")
+	passivePtr := flag.Bool("passive", false, "Discover subdomains from passive OSINT sources only, skipping DNS brute-force")
+	activePtr := flag.Bool("active", false, "Brute-force subdomains from the wordlist only (default)")
+	allPtr := flag.Bool("all", false, "Discover subdomains from passive sources, then validate them alongside the wordlist brute-force")
+	permutePtr := flag.Bool("permute", false, "Run a second pass resolving name alterations generated from discovered subdomains")
+	permEnvsPtr := flag.String("perm-envs", "", "Comma-separated environment tokens to swap during permutation (default: dev,stage,staging,prod,qa)")
+	permMaxSuffixPtr := flag.Int("perm-max-suffix", 3, "Highest numeric suffix generated during permutation")
+	resolversPtr := flag.String("resolvers", "", "Comma-separated upstream DNS resolvers to query (default: 1.1.1.1,8.8.8.8,9.9.9.9)")
+	resolversFilePtr := flag.String("resolvers-file", "", "Path to a file listing upstream DNS resolvers, one per line")
+	noCachePtr := flag.Bool("no-cache", false, "Disable the persistent on-disk resolution cache")
+	cachePathPtr := flag.String("cache-path", "", "Path to the persistent cache file (default: ~/.xorn/cache.db)")
+	cacheTTLPtr := flag.Duration("cache-ttl", cache.DefaultTTL, "TTL for cached results lacking a DNS-supplied TTL")
+	asnSweepPtr := flag.Bool("asn-sweep", false, "Expand discovered IPs into their announced netblocks and reverse-DNS sweep them for more subdomains")
+	maxNetblockPtr := flag.Int("max-netblock", 24, "Largest netblock (by prefix length) that -asn-sweep will sweep")
+	ctSincePtr := flag.Duration("ct-since", 0, "Tail CT logs for certificates issued within this window, resuming from the last scan's cursor (default: crt.sh full history only)")
 	flag.Parse()
 
 	if *domainPtr == "" {
-		fmt.Println("Usage: xorn -d <domain> [-t <threads>] [--timeout <timeout>] [--retry <retry>] [--retry-wait <retry-wait>] [-o <output-file>] [--separator <separator>] [-w <wordlist-file>] [--rate-limit <rate-limit>] [--batch-size <batch-size>] [--status-code] [--title]")
+		fmt.Println("Usage: xorn -d <domain> [-t <threads>] [--timeout <timeout>] [--retry <retry>] [--retry-wait <retry-wait>] [-o <output-file>] [-oJ | -oL | -oC | -oT | -output-format <format>] [-w <wordlist-file>] [--rate-limit <rate-limit>] [--batch-size <batch-size>] [--status-code] [--title] [-passive | -active | -all] [-sources <names>] [-sources-config <file>] [-permute] [-perm-envs <tokens>] [-perm-max-suffix <n>] [-resolvers <ips>] [-resolvers-file <file>] [-no-cache] [-cache-path <file>] [-cache-ttl <duration>] [-asn-sweep] [-max-netblock <prefix-len>] [-ct-since <duration>]")
+		fmt.Println("       xorn cache purge [-cache-path <file>]")
 		return
 	}
 
+	outputFormat := output.Format(*outputFormatPtr)
+	switch {
+	case *outputJSONPtr:
+		outputFormat = output.FormatJSON
+	case *outputJSONLPtr:
+		outputFormat = output.FormatJSONL
+	case *outputCSVPtr:
+		outputFormat = output.FormatCSV
+	case *outputTextPtr:
+		outputFormat = output.FormatText
+	}
+
+	var sources []string
+	if *sourcesPtr != "" {
+		sources = strings.Split(*sourcesPtr, ",")
+	}
+
+	var permEnvTokens []string
+	if *permEnvsPtr != "" {
+		permEnvTokens = strings.Split(*permEnvsPtr, ",")
+	}
+
+	var resolvers []string
+	if *resolversPtr != "" {
+		resolvers = strings.Split(*resolversPtr, ",")
+	}
+
 	config := Config{
-		Domain:          *domainPtr,
-		Threads:         *threadsPtr,
-		Timeout:         *timeoutPtr,
-		Retry:           *retryPtr,
-		RetryWait:       *retryWaitPtr,
-		OutputFile:      *outputFilePtr,
-		OutputSeparator: *outputSeparatorPtr,
-		WordlistFile:    *wordlistFilePtr,
-		RateLimit:       *rateLimitPtr,
-		BatchSize:       *batchSizePtr,
-		StatusCode:      *statusCodePtr,
-		Title:           *titlePtr,
+		Domain:        *domainPtr,
+		Threads:       *threadsPtr,
+		Timeout:       *timeoutPtr,
+		Retry:         *retryPtr,
+		RetryWait:     *retryWaitPtr,
+		OutputFile:    *outputFilePtr,
+		OutputFormat:  outputFormat,
+		WordlistFile:  *wordlistFilePtr,
+		RateLimit:     *rateLimitPtr,
+		BatchSize:     *batchSizePtr,
+		StatusCode:    *statusCodePtr,
+		Title:         *titlePtr,
+		Sources:       sources,
+		SourcesConfig: *sourcesConfigPtr,
+		Passive:       *passivePtr,
+		Active:        *activePtr,
+		All:           *allPtr,
+		Permute:       *permutePtr,
+		PermEnvTokens: permEnvTokens,
+		PermMaxSuffix: *permMaxSuffixPtr,
+		Resolvers:     resolvers,
+		ResolversFile: *resolversFilePtr,
+		NoCache:       *noCachePtr,
+		CachePath:     *cachePathPtr,
+		CacheTTL:      *cacheTTLPtr,
+		AsnSweep:      *asnSweepPtr,
+		MaxNetblock:   *maxNetblockPtr,
+		CTSince:       *ctSincePtr,
+	}
+
+	// Default to the historical brute-force-only behavior when no mode
+	// flag is given.
+	if !config.Passive && !config.Active && !config.All {
+		config.Active = true
 	}
 
 	var subdomains []string
 
-	// Load subdomains from wordlist file if provided
-	if config.WordlistFile != "" {
+	// Brute-force candidates come from the wordlist, one mode selector at a time.
+	if config.Active || config.All {
+		if config.WordlistFile == "" {
+			fmt.Println("Error: No wordlist file provided")
+			return
+		}
 		loadedSubdomains, err := LoadWordlist(config.WordlistFile)
 		if err != nil {
 			fmt.Println("Error loading wordlist file:", err)
@@ -284,32 +674,121 @@ func main() {
 		for _, subdomain := range loadedSubdomains {
 			subdomains = append(subdomains, subdomain+"."+config.Domain)
 		}
-	} else {
-		fmt.Println("Error: No wordlist file provided")
-		return
 	}
 
 	// Create and run subdomain scanner
 	scanner := NewScanner(config)
-	foundSubdomains := scanner.ScanSubdomains(subdomains)
 
-	// Output found subdomains
-	if len(foundSubdomains) > 0 {
-		fmt.Println("Found subdomains:")
-		for _, subdomain := range foundSubdomains {
-			fmt.Println(subdomain)
+	// Results are written to the output file if one was given, or to
+	// stdout otherwise, in whichever format was selected.
+	dest := io.Writer(os.Stdout)
+	if config.OutputFile != "" {
+		file, err := os.Create(config.OutputFile)
+		if err != nil {
+			fmt.Println("Error creating output file:", err)
+			return
 		}
-	} else {
-		fmt.Println("No subdomains found.")
+		defer file.Close()
+		dest = file
+	}
+	writer, err := output.New(config.OutputFormat, dest)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	scanner.SetWriter(writer)
+	defer writer.Close()
+
+	// Probe for a wildcard DNS record before resolving anything so that
+	// brute-forced candidates which all resolve to the same catch-all IP
+	// aren't reported as found subdomains.
+	if err := scanner.DetectWildcard(context.Background()); err != nil {
+		fmt.Println("Warning: wildcard detection failed:", err)
+	}
+
+	subdomains = dedupSubdomains(subdomains)
+	var foundSubdomains []string
+	if len(subdomains) > 0 {
+		foundSubdomains = append(foundSubdomains, scanner.ScanSubdomains(subdomains)...)
+	}
+
+	// Passive candidates are discovered up front and scanned separately
+	// from the wordlist, so each Result keeps the right Source; in -all
+	// mode the two overlap often enough (e.g. "www") that a name already
+	// resolved from the wordlist is excluded here rather than resolved
+	// (and emitted) a second time under "passive".
+	if config.Passive || config.All {
+		passiveSubdomains, err := scanner.DiscoverPassive(context.Background())
+		if err != nil {
+			fmt.Println("Error running passive discovery:", err)
+			return
+		}
+		passiveSubdomains = dedupAgainst(passiveSubdomains, subdomains)
+		foundSubdomains = append(foundSubdomains, scanner.ScanPassiveSubdomains(passiveSubdomains)...)
 	}
 
-	// Write to output file if specified
+	// Run a second resolution pass over name alterations generated from
+	// what was just discovered.
+	if config.Permute {
+		foundSubdomains = append(foundSubdomains, scanner.PermuteAndScan(foundSubdomains)...)
+	}
+
+	// Expand discovered IPs into their announced netblocks and
+	// reverse-DNS sweep them for subdomains pointed at the same
+	// infrastructure but missed by the wordlist, passive sources, or
+	// permutation.
+	if config.AsnSweep {
+		foundSubdomains = append(foundSubdomains, scanner.ASNSweepAndScan(context.Background())...)
+	}
+
+	// Each result was already emitted to the configured writer as it was
+	// discovered; report a final count to stderr so it doesn't pollute
+	// structured stdout output.
 	if config.OutputFile != "" {
-		err := scanner.WriteToFile(foundSubdomains)
+		fmt.Fprintln(os.Stderr, len(foundSubdomains), "subdomains found, results saved to", config.OutputFile)
+	} else {
+		fmt.Fprintln(os.Stderr, len(foundSubdomains), "subdomains found")
+	}
+
+	if err := scanner.SaveCache(); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not save cache:", err)
+	}
+	if err := scanner.SaveCTCursor(); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not save CT log cursor:", err)
+	}
+}
+
+// runCacheCommand implements the "xorn cache <subcommand>" family, kept
+// separate from the scan flags since it operates on the cache file
+// directly rather than running a scan.
+func runCacheCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: xorn cache <purge> [-cache-path <file>]")
+		return
+	}
+
+	fs := flag.NewFlagSet("cache "+args[0], flag.ExitOnError)
+	cachePathPtr := fs.String("cache-path", "", "Path to the persistent cache file (default: ~/.xorn/cache.db)")
+	fs.Parse(args[1:])
+
+	path := *cachePathPtr
+	if path == "" {
+		defaultPath, err := cache.DefaultPath()
 		if err != nil {
-			fmt.Println("Error writing to output file:", err)
-		} else {
-			fmt.Println("Results saved to", config.OutputFile)
+			fmt.Fprintln(os.Stderr, "Error resolving default cache path:", err)
+			return
+		}
+		path = defaultPath
+	}
+
+	switch args[0] {
+	case "purge":
+		if err := cache.Purge(path); err != nil {
+			fmt.Fprintln(os.Stderr, "Error purging cache:", err)
+			return
 		}
+		fmt.Println("Cache purged:", path)
+	default:
+		fmt.Println("Usage: xorn cache <purge> [-cache-path <file>]")
 	}
 }